@@ -0,0 +1,609 @@
+package rediscensus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	keyPoolName       = tag.MustNewKey("pool.name")
+	keyType           = tag.MustNewKey("type")
+	keyStatus         = tag.MustNewKey("status")
+	keyState          = tag.MustNewKey("state")
+	keyRole           = tag.MustNewKey("role")
+	keySentinelMaster = tag.MustNewKey("sentinel.master")
+	keyOperation      = tag.MustNewKey("db.operation")
+	keyErrorType      = tag.MustNewKey("error.type")
+	keyReason         = tag.MustNewKey("reason")
+)
+
+var (
+	measureCreateTime = stats.Float64(
+		"db/client/connections/create_time",
+		"The time it took to create a new connection.",
+		stats.UnitMilliseconds,
+	)
+	measureUseTime = stats.Float64(
+		"db/client/connections/use_time",
+		"The time between borrowing a connection and returning it to the pool.",
+		stats.UnitMilliseconds,
+	)
+	measureOperationDuration = stats.Float64(
+		"db/client/operation/duration",
+		"Duration of individual Redis commands, broken down by db.operation.",
+		stats.UnitMilliseconds,
+	)
+	measureWaitTime = stats.Float64(
+		"db/client/connections/wait_time",
+		"The time it took to wait for a connection from the pool.",
+		stats.UnitMilliseconds,
+	)
+
+	measureIdleMax = stats.Int64(
+		"db/client/connections/idle_max",
+		"The maximum number of idle open connections allowed",
+		stats.UnitDimensionless,
+	)
+	measureIdleMin = stats.Int64(
+		"db/client/connections/idle_min",
+		"The minimum number of idle open connections allowed",
+		stats.UnitDimensionless,
+	)
+	measureConnsMax = stats.Int64(
+		"db/client/connections/max",
+		"The maximum number of open connections allowed",
+		stats.UnitDimensionless,
+	)
+	measureUsage = stats.Int64(
+		"db/client/connections/usage",
+		"The number of connections currently in the state described by the state tag",
+		stats.UnitDimensionless,
+	)
+	measureTimeouts = stats.Int64(
+		"db/client/connections/timeouts",
+		"The number of connection timeouts that have occurred trying to obtain a connection from the pool",
+		stats.UnitDimensionless,
+	)
+	measureHits = stats.Int64(
+		"db/client/connections/hits",
+		"The number of times free connection was found in the pool",
+		stats.UnitDimensionless,
+	)
+	measureMisses = stats.Int64(
+		"db/client/connections/misses",
+		"The number of times free connection was not found in the pool",
+		stats.UnitDimensionless,
+	)
+	measureStaleClosed = stats.Int64(
+		"db/client/connections/stale_closed",
+		"The number of idle connections closed for exceeding MaxConnAge/ConnMaxLifetime",
+		stats.UnitDimensionless,
+	)
+	measurePending = stats.Int64(
+		"db/client/connections/pending",
+		"The number of goroutines currently blocked waiting for a connection from the pool",
+		stats.UnitDimensionless,
+	)
+	measureAcquireFailures = stats.Int64(
+		"db/client/connections/acquire_failures",
+		"The number of times a connection could not be acquired from the pool.",
+		stats.UnitDimensionless,
+	)
+)
+
+// defaultViews are the OpenCensus views registered by InstrumentMetrics. Counters use
+// a Sum aggregation, gauges use LastValue, and the latency measures use Distribution
+// with the millisecond bucket boundaries listed below.
+var defaultViews = []*view.View{
+	{
+		Name:        "db.client.connections.create_time",
+		Measure:     measureCreateTime,
+		TagKeys:     []tag.Key{keyPoolName, keyStatus},
+		Aggregation: view.Distribution(0, 1, 2, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000),
+	},
+	{
+		Name:        "db.client.connections.use_time",
+		Measure:     measureUseTime,
+		TagKeys:     []tag.Key{keyPoolName, keyType, keyStatus, keyErrorType},
+		Aggregation: view.Distribution(0, 1, 2, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000),
+	},
+	{
+		Name:        "db.client.operation.duration",
+		Measure:     measureOperationDuration,
+		TagKeys:     []tag.Key{keyPoolName, keyOperation, keyStatus, keyErrorType},
+		Aggregation: view.Distribution(0, 1, 2, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000),
+	},
+	{
+		Name:        "db.client.connections.wait_time",
+		Measure:     measureWaitTime,
+		TagKeys:     []tag.Key{keyPoolName},
+		Aggregation: view.Distribution(0, 1, 2, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000),
+	},
+	{Name: "db.client.connections.idle.max", Measure: measureIdleMax, TagKeys: []tag.Key{keyPoolName}, Aggregation: view.LastValue()},
+	{Name: "db.client.connections.idle.min", Measure: measureIdleMin, TagKeys: []tag.Key{keyPoolName}, Aggregation: view.LastValue()},
+	{Name: "db.client.connections.max", Measure: measureConnsMax, TagKeys: []tag.Key{keyPoolName}, Aggregation: view.LastValue()},
+	{Name: "db.client.connections.usage", Measure: measureUsage, TagKeys: []tag.Key{keyPoolName, keyState}, Aggregation: view.LastValue()},
+	{Name: "db.client.connections.timeouts", Measure: measureTimeouts, TagKeys: []tag.Key{keyPoolName}, Aggregation: view.Sum()},
+	{Name: "db.client.connections.hits", Measure: measureHits, TagKeys: []tag.Key{keyPoolName}, Aggregation: view.Sum()},
+	{Name: "db.client.connections.misses", Measure: measureMisses, TagKeys: []tag.Key{keyPoolName}, Aggregation: view.Sum()},
+	{Name: "db.client.connections.stale_closed", Measure: measureStaleClosed, TagKeys: []tag.Key{keyPoolName}, Aggregation: view.LastValue()},
+	{Name: "db.client.connections.pending", Measure: measurePending, TagKeys: []tag.Key{keyPoolName}, Aggregation: view.LastValue()},
+	{
+		Name:        "db.client.connections.acquire.failures",
+		Measure:     measureAcquireFailures,
+		TagKeys:     []tag.Key{keyPoolName, keyReason},
+		Aggregation: view.Sum(),
+	},
+}
+
+var (
+	viewMu       sync.Mutex
+	viewRefCount int
+)
+
+func acquireViews() error {
+	viewMu.Lock()
+	defer viewMu.Unlock()
+
+	if viewRefCount == 0 {
+		if err := view.Register(defaultViews...); err != nil {
+			return err
+		}
+	}
+	viewRefCount++
+	return nil
+}
+
+func releaseViews() {
+	viewMu.Lock()
+	defer viewMu.Unlock()
+
+	viewRefCount--
+	if viewRefCount <= 0 {
+		viewRefCount = 0
+		view.Unregister(defaultViews...)
+	}
+}
+
+const defaultPollInterval = 10 * time.Second
+
+type metricsConfig struct {
+	poolName     string
+	pollInterval time.Duration
+	closeChan    <-chan struct{}
+
+	// masterName, when set, is reported as the sentinel.master tag for every client
+	// instrumented with this config, whether or not it is part of a Sentinel topology.
+	masterName string
+
+	// commandMetricsEnabled gates the per-command db.client.operation.duration
+	// measure, which is opt-in because it carries one series per distinct Redis
+	// command.
+	commandMetricsEnabled bool
+}
+
+// MetricsOption configures InstrumentMetrics.
+type MetricsOption func(conf *metricsConfig)
+
+// WithPoolName sets the pool.name tag attached to every measurement. It defaults to
+// the client's Options().Addr.
+func WithPoolName(name string) MetricsOption {
+	return func(conf *metricsConfig) {
+		conf.poolName = name
+	}
+}
+
+// WithPollInterval sets how often pool gauges (idle.max, usage, hits, ...) are
+// sampled and recorded, since OpenCensus has no observable-gauge equivalent of
+// OpenTelemetry's async instruments. It defaults to 10s.
+func WithPollInterval(d time.Duration) MetricsOption {
+	return func(conf *metricsConfig) {
+		conf.pollInterval = d
+	}
+}
+
+// WithCloseChan takes a channel that, once closed, stops pool-stat polling and
+// unregisters the OpenCensus views once every InstrumentMetrics caller using them has
+// also closed its channel, matching redisotel's teardown semantics.
+func WithCloseChan(closeChan <-chan struct{}) MetricsOption {
+	return func(conf *metricsConfig) {
+		conf.closeChan = closeChan
+	}
+}
+
+// WithMasterName sets the Sentinel master name to report as the sentinel.master tag.
+// Use it alongside a client built from redis.NewFailoverClient,
+// redis.NewFailoverClusterClient, or a *redis.SentinelClient, since none of them
+// retain the FailoverOptions/SentinelOptions master name after construction. The tag
+// is added to every client it is passed with, including clients that aren't part of
+// a Sentinel topology.
+func WithMasterName(name string) MetricsOption {
+	return func(conf *metricsConfig) {
+		conf.masterName = name
+	}
+}
+
+// WithCommandMetrics enables the per-command db.client.operation.duration measure,
+// tagged with db.operation. It is disabled by default because it adds one series per
+// distinct Redis command; the error.type tag on db.client.connections.use_time is
+// always recorded regardless of this setting.
+func WithCommandMetrics(enabled bool) MetricsOption {
+	return func(conf *metricsConfig) {
+		conf.commandMetricsEnabled = enabled
+	}
+}
+
+// redisConn is the subset of *redis.Client and *redis.SentinelClient that
+// rediscensus needs to report pool stats and install hooks.
+type redisConn interface {
+	Options() *redis.Options
+	PoolStats() *redis.PoolStats
+	AddHook(redis.Hook)
+}
+
+// InstrumentMetrics starts reporting OpenCensus Metrics.
+//
+// Based on https://github.com/open-telemetry/opentelemetry-specification/blob/main/specification/metrics/semantic_conventions/database-metrics.md
+func InstrumentMetrics(rdb redis.UniversalClient, opts ...MetricsOption) error {
+	conf := &metricsConfig{pollInterval: defaultPollInterval}
+	for _, opt := range opts {
+		opt(conf)
+	}
+
+	if err := acquireViews(); err != nil {
+		return err
+	}
+	if conf.closeChan != nil {
+		go func() {
+			<-conf.closeChan
+			releaseViews()
+		}()
+	}
+
+	switch rdb := rdb.(type) {
+	case *redis.Client:
+		return registerClient(rdb, conf, []tag.Mutator{tag.Upsert(keyRole, "master")})
+	case *redis.ClusterClient:
+		// role is a client-wide guess, not a per-node fact: OnNewNode below hands us a
+		// *redis.Client per physical node as the cluster topology is discovered, but
+		// that callback doesn't tell us whether the node it's instrumenting is the
+		// master or a replica. When ReadOnly is set this still tags every node
+		// "replica", which mislabels the master node's traffic, since writes (and any
+		// reads the client doesn't route to a replica) still go through the master
+		// using this same instrumented client. Revisit this once/if OnNewNode or the
+		// per-node Options exposes the node's actual role.
+		role := "master"
+		if rdb.Options().ReadOnly {
+			role = "replica"
+		}
+		mutators := []tag.Mutator{tag.Upsert(keyRole, role)}
+		rdb.OnNewNode(func(rdb *redis.Client) {
+			// registerClient only fails if OpenCensus rejects the tag set, which would
+			// fail identically for every node; there's nothing a caller could do with
+			// the error from inside this callback, so it's dropped rather than logged
+			// (OpenCensus, unlike OpenTelemetry's otel.Handle, has no package-wide error
+			// reporting convention to plug into instead).
+			_ = registerClient(rdb, conf, mutators)
+		})
+		return nil
+	case *redis.Ring:
+		rdb.OnNewNode(func(rdb *redis.Client) {
+			_ = registerClient(rdb, conf, nil)
+		})
+		return nil
+	case *redis.SentinelClient:
+		return registerClient(rdb, conf, []tag.Mutator{tag.Upsert(keyRole, "sentinel")})
+	default:
+		return fmt.Errorf("rediscensus: %T not supported", rdb)
+	}
+}
+
+func registerClient(rdb redisConn, conf *metricsConfig, mutators []tag.Mutator) error {
+	poolName := conf.poolName
+	if poolName == "" {
+		poolName = rdb.Options().Addr
+	}
+
+	mutators = append([]tag.Mutator{tag.Upsert(keyPoolName, poolName)}, mutators...)
+	if conf.masterName != "" {
+		mutators = append(mutators, tag.Upsert(keySentinelMaster, conf.masterName))
+	}
+
+	ctx, err := tag.New(context.Background(), mutators...)
+	if err != nil {
+		return err
+	}
+
+	// InstrumentMetrics is documented to run on an already-constructed client, at
+	// which point its ConnPool already exists: PoolObserver is a
+	// *redis.ObserverHandle, not a plain field, precisely so installing the observer
+	// here still takes effect. Options and the pool's own internal config are copied
+	// by value at construction time, but the *redis.ObserverHandle pointer they share
+	// is not, so the pool's next Get/Put observes whatever was last stored here.
+	observer := &poolObserver{ctx: ctx}
+	rdb.Options().PoolObserver.Store(observer)
+
+	go pollPoolStats(ctx, rdb, observer, conf.pollInterval, conf.closeChan)
+
+	rdb.AddHook(&metricsHook{ctx: ctx, commandMetricsEnabled: conf.commandMetricsEnabled})
+	return nil
+}
+
+func pollPoolStats(ctx context.Context, rdb redisConn, observer *poolObserver, interval time.Duration, closeChan <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// prev is the previous sample, so the Sum-aggregated counters record only what
+	// changed since it rather than the pool's running totals on every tick.
+	var prev redis.PoolStats
+	for {
+		select {
+		case <-ticker.C:
+			prev = recordPoolStats(ctx, rdb, observer, prev)
+		case <-closeChan:
+			return
+		}
+	}
+}
+
+func recordPoolStats(ctx context.Context, rdb redisConn, observer *poolObserver, prev redis.PoolStats) redis.PoolStats {
+	opt := rdb.Options()
+	s := rdb.PoolStats()
+
+	idleCtx, err := tag.New(ctx, tag.Upsert(keyState, "idle"))
+	if err != nil {
+		return prev
+	}
+	usedCtx, err := tag.New(ctx, tag.Upsert(keyState, "used"))
+	if err != nil {
+		return prev
+	}
+
+	stats.Record(ctx, measureIdleMax.M(int64(opt.MaxIdleConns)))
+	stats.Record(ctx, measureIdleMin.M(int64(opt.MinIdleConns)))
+	stats.Record(ctx, measureConnsMax.M(int64(opt.PoolSize)))
+	stats.Record(idleCtx, measureUsage.M(int64(s.IdleConns)))
+	stats.Record(usedCtx, measureUsage.M(int64(s.TotalConns-s.IdleConns)))
+	stats.Record(ctx, measureTimeouts.M(int64(s.Timeouts-prev.Timeouts)))
+	stats.Record(ctx, measureHits.M(int64(s.Hits-prev.Hits)))
+	stats.Record(ctx, measureMisses.M(int64(s.Misses-prev.Misses)))
+	stats.Record(ctx, measureStaleClosed.M(int64(s.StaleConns)))
+	if observer != nil {
+		stats.Record(ctx, measurePending.M(observer.Pending()))
+	}
+	return *s
+}
+
+// poolObserver records pool wait-time and acquire-failure measures and tracks the
+// number of goroutines currently blocked in Get. Its method set matches the Observer
+// interface internal/pool defines (OnGetStart/OnGet/OnPut), which is how
+// Options().PoolObserver.Store above is able to accept it without this package
+// needing to import that internal package itself: extra/rediscensus is a separate Go
+// module from github.com/redis/go-redis/v9, so it cannot reach anything under
+// .../v9/internal/... regardless of the replace directive pointing it back at the
+// root module.
+type poolObserver struct {
+	ctx     context.Context
+	pending int64
+}
+
+// poolObserverShape documents, and lets the compiler check, the Observer method set
+// poolObserver must satisfy without naming the (unimportable) internal/pool.Observer
+// interface directly.
+type poolObserverShape interface {
+	OnGetStart()
+	OnGet(time.Duration, error)
+	OnPut()
+}
+
+var _ poolObserverShape = (*poolObserver)(nil)
+
+func (po *poolObserver) OnGetStart() {
+	atomic.AddInt64(&po.pending, 1)
+}
+
+func (po *poolObserver) OnGet(waited time.Duration, err error) {
+	atomic.AddInt64(&po.pending, -1)
+
+	stats.Record(po.ctx, measureWaitTime.M(milliseconds(waited)))
+
+	if err == nil {
+		return
+	}
+
+	tagCtx, tagErr := tag.New(po.ctx, tag.Upsert(keyReason, acquireFailureReason(err)))
+	if tagErr == nil {
+		stats.Record(tagCtx, measureAcquireFailures.M(1))
+	}
+}
+
+func (po *poolObserver) OnPut() {}
+
+func (po *poolObserver) Pending() int64 {
+	return atomic.LoadInt64(&po.pending)
+}
+
+// acquireFailureReason classifies the error OnGet reports when Get fails to
+// acquire a connection. redis.ErrPoolTimeout/redis.ErrClosed are the root package's
+// re-exports of the sentinel errors internal/pool.ConnPool.Get returns; this package
+// cannot reference internal/pool directly (see poolObserver's doc comment), so it
+// checks the public aliases instead. This mirrors extra/redisotel's classifier; it
+// isn't factored into a shared helper since the two packages are separate Go modules
+// with no common non-internal home for it to live in.
+func acquireFailureReason(err error) string {
+	var netErr *net.OpError
+	switch {
+	case errors.Is(err, redis.ErrPoolTimeout), errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "context_canceled"
+	case errors.Is(err, redis.ErrClosed):
+		return "closed"
+	case errors.As(err, &netErr):
+		return "dial_error"
+	default:
+		return "dial_error"
+	}
+}
+
+type metricsHook struct {
+	ctx context.Context
+
+	// commandMetricsEnabled gates measureOperationDuration, since it carries one
+	// series per distinct Redis command and is opt-in to avoid blowing up cardinality
+	// on the default setup.
+	commandMetricsEnabled bool
+}
+
+var _ redis.Hook = (*metricsHook)(nil)
+
+func (mh *metricsHook) DialHook(hook redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		start := time.Now()
+
+		conn, err := hook(ctx, network, addr)
+
+		dur := time.Since(start)
+
+		tagCtx, tagErr := tag.New(mh.ctx, tag.Upsert(keyStatus, statusString(err)))
+		if tagErr == nil {
+			stats.Record(tagCtx, measureCreateTime.M(milliseconds(dur)))
+		}
+		return conn, err
+	}
+}
+
+func (mh *metricsHook) ProcessHook(hook redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+
+		err := hook(ctx, cmd)
+
+		dur := time.Since(start)
+
+		mutators := []tag.Mutator{tag.Upsert(keyType, "command"), tag.Upsert(keyStatus, statusString(err))}
+		if errType := errorType(err); errType != "" {
+			mutators = append(mutators, tag.Upsert(keyErrorType, errType))
+		}
+		tagCtx, tagErr := tag.New(mh.ctx, mutators...)
+		if tagErr == nil {
+			stats.Record(tagCtx, measureUseTime.M(milliseconds(dur)))
+		}
+
+		if mh.commandMetricsEnabled {
+			mh.recordOperationDuration(cmd.Name(), dur, err)
+		}
+
+		return err
+	}
+}
+
+func (mh *metricsHook) ProcessPipelineHook(
+	hook redis.ProcessPipelineHook,
+) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+
+		err := hook(ctx, cmds)
+
+		dur := time.Since(start)
+
+		mutators := []tag.Mutator{tag.Upsert(keyType, "pipeline"), tag.Upsert(keyStatus, statusString(err))}
+		if errType := errorType(err); errType != "" {
+			mutators = append(mutators, tag.Upsert(keyErrorType, errType))
+		}
+		tagCtx, tagErr := tag.New(mh.ctx, mutators...)
+		if tagErr == nil {
+			stats.Record(tagCtx, measureUseTime.M(milliseconds(dur)))
+		}
+
+		if mh.commandMetricsEnabled {
+			for _, cmd := range cmds {
+				mh.recordOperationDuration(cmd.Name(), dur, cmd.Err())
+			}
+		}
+
+		return err
+	}
+}
+
+func (mh *metricsHook) recordOperationDuration(cmdName string, dur time.Duration, err error) {
+	mutators := []tag.Mutator{
+		tag.Upsert(keyOperation, strings.ToUpper(cmdName)),
+		tag.Upsert(keyStatus, statusString(err)),
+	}
+	if errType := errorType(err); errType != "" {
+		mutators = append(mutators, tag.Upsert(keyErrorType, errType))
+	}
+	tagCtx, tagErr := tag.New(mh.ctx, mutators...)
+	if tagErr == nil {
+		stats.Record(tagCtx, measureOperationDuration.M(milliseconds(dur)))
+	}
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func statusString(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
+
+// errorType classifies err into a low-cardinality error.type tag value, or "" if err
+// is nil, mirroring redisotel's classification so the two signals agree. It isn't
+// factored into a shared helper since the two packages are separate Go modules with
+// no common non-internal home for it to live in; keep them in sync by hand when the
+// classification changes.
+func errorType(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var netErr *net.OpError
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.As(err, &netErr):
+		if netErr.Timeout() {
+			return "timeout"
+		}
+		return "network"
+	}
+
+	msg := err.Error()
+	switch {
+	case strings.HasPrefix(msg, "MOVED"):
+		return "moved"
+	case strings.HasPrefix(msg, "ASK"):
+		return "ask"
+	case strings.HasPrefix(msg, "LOADING"):
+		return "loading"
+	case strings.HasPrefix(msg, "READONLY"):
+		return "readonly"
+	case strings.HasPrefix(msg, "NOSCRIPT"):
+		return "noscript"
+	case strings.HasPrefix(msg, "WRONGTYPE"):
+		return "wrongtype"
+	case strings.Contains(msg, "i/o timeout"):
+		return "timeout"
+	default:
+		return "other"
+	}
+}
@@ -0,0 +1,65 @@
+package rediscensus
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestErrorType(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"nil", nil, ""},
+		{"deadline exceeded", context.DeadlineExceeded, "timeout"},
+		{"moved", errors.New("MOVED 1 127.0.0.1:6380"), "moved"},
+		{"other", errors.New("boom"), "other"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorType(tt.err); got != tt.want {
+				t.Fatalf("errorType(%v) = %q, want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAcquireFailureReason(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"pool timeout", redis.ErrPoolTimeout, "timeout"},
+		{"context canceled", context.Canceled, "context_canceled"},
+		{"pool closed", redis.ErrClosed, "closed"},
+		{"dial error", &net.OpError{Op: "dial", Err: errors.New("refused")}, "dial_error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := acquireFailureReason(tt.err); got != tt.want {
+				t.Fatalf("acquireFailureReason(%v) = %q, want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithMasterNameAndCommandMetrics(t *testing.T) {
+	conf := &metricsConfig{}
+	WithMasterName("mymaster")(conf)
+	WithCommandMetrics(true)(conf)
+
+	if conf.masterName != "mymaster" {
+		t.Fatalf("masterName = %q, want %q", conf.masterName, "mymaster")
+	}
+	if !conf.commandMetricsEnabled {
+		t.Fatal("commandMetricsEnabled = false, want true")
+	}
+}
@@ -0,0 +1,134 @@
+package redisotel
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumName = "github.com/redis/go-redis/extra/redisotel"
+
+// config holds the options shared by InstrumentMetrics and InstrumentTracing. Both
+// accept a slice of baseOption so a single config can be built regardless of which
+// signal is being instrumented.
+type config struct {
+	attrs     []attribute.KeyValue
+	closeChan chan struct{}
+	poolName  string
+
+	// masterName, when set, is reported as the sentinel.master attribute for every
+	// *redis.Client, *redis.ClusterClient and *redis.SentinelClient instrumented with
+	// this config, whether or not it is part of a Sentinel topology.
+	masterName string
+
+	mp    metric.MeterProvider
+	meter metric.Meter
+
+	// commandMetricsEnabled gates the per-command db.client.operation.duration
+	// histogram, which is opt-in because it carries one series per distinct Redis
+	// command.
+	commandMetricsEnabled bool
+
+	tp     trace.TracerProvider
+	tracer trace.Tracer
+}
+
+func newConfig(opts ...baseOption) *config {
+	conf := &config{
+		attrs: make([]attribute.KeyValue, 0),
+		mp:    otel.GetMeterProvider(),
+		tp:    otel.GetTracerProvider(),
+	}
+	for _, opt := range opts {
+		opt.apply(conf)
+	}
+	return conf
+}
+
+// baseOption is implemented by both MetricsOption and TracingOption so the two can
+// share the same config and the same With* constructors where it makes sense.
+type baseOption interface {
+	apply(conf *config)
+}
+
+type option func(conf *config)
+
+func (fn option) apply(conf *config) {
+	fn(conf)
+}
+
+// MetricsOption configures InstrumentMetrics.
+type MetricsOption interface {
+	baseOption
+}
+
+// TracingOption configures InstrumentTracing.
+type TracingOption interface {
+	baseOption
+}
+
+// WithAttributes appends attributes to every metric/span recorded for the
+// instrumented client, in addition to pool.name, role (for every client type except
+// *redis.Ring) and, when WithMasterName is set, sentinel.master.
+func WithAttributes(attrs ...attribute.KeyValue) MetricsOption {
+	return option(func(conf *config) {
+		conf.attrs = append(conf.attrs, attrs...)
+	})
+}
+
+// WithMeterProvider sets the OpenTelemetry metric.MeterProvider used to create the
+// meter. It defaults to the global provider.
+func WithMeterProvider(mp metric.MeterProvider) MetricsOption {
+	return option(func(conf *config) {
+		conf.mp = mp
+	})
+}
+
+// WithTracerProvider sets the OpenTelemetry trace.TracerProvider used to create the
+// tracer. It defaults to the global provider.
+func WithTracerProvider(tp trace.TracerProvider) TracingOption {
+	return option(func(conf *config) {
+		conf.tp = tp
+	})
+}
+
+// WithPoolName sets the pool.name attribute attached to every measurement/span. It
+// defaults to the client's Options().Addr.
+func WithPoolName(name string) MetricsOption {
+	return option(func(conf *config) {
+		conf.poolName = name
+	})
+}
+
+// WithCloseChan takes a channel that, once closed, unregisters every metric
+// registration InstrumentMetrics has made for the instrumented client(s).
+func WithCloseChan(closeChan chan struct{}) MetricsOption {
+	return option(func(conf *config) {
+		conf.closeChan = closeChan
+	})
+}
+
+// WithMasterName sets the Sentinel master name to report as the `sentinel.master`
+// attribute. Use it alongside a client built from redis.NewFailoverClient,
+// redis.NewFailoverClusterClient, or a *redis.SentinelClient, since none of them
+// retain the FailoverOptions/SentinelOptions master name after construction. The
+// attribute is added to any *redis.Client, *redis.ClusterClient or
+// *redis.SentinelClient it is passed with, since a failover client cannot be told
+// apart from a plain one; it is not added for a *redis.Ring.
+func WithMasterName(name string) MetricsOption {
+	return option(func(conf *config) {
+		conf.masterName = name
+	})
+}
+
+// WithCommandMetrics enables the per-command db.client.operation.duration histogram,
+// tagged with db.operation (and, for pipelines, pipeline.size). It is disabled by
+// default because it adds one series per distinct Redis command; the error.type
+// attribute on db.client.connections.use_time is always recorded regardless of this
+// setting.
+func WithCommandMetrics(enabled bool) MetricsOption {
+	return option(func(conf *config) {
+		conf.commandMetricsEnabled = enabled
+	})
+}
@@ -0,0 +1,24 @@
+package redisotel
+
+import "testing"
+
+func TestWithMasterName(t *testing.T) {
+	conf := newConfig(WithMasterName("mymaster"))
+	if conf.masterName != "mymaster" {
+		t.Fatalf("masterName = %q, want %q", conf.masterName, "mymaster")
+	}
+}
+
+func TestAddMasterNameAttr(t *testing.T) {
+	conf := newConfig(WithMasterName("mymaster"))
+	addMasterNameAttr(conf)
+	if len(conf.attrs) != 1 || conf.attrs[0].Value.AsString() != "mymaster" {
+		t.Fatalf("attrs = %v, want a single sentinel.master=mymaster attribute", conf.attrs)
+	}
+
+	conf = newConfig()
+	addMasterNameAttr(conf)
+	if len(conf.attrs) != 0 {
+		t.Fatalf("attrs = %v, want no attributes when masterName is unset", conf.attrs)
+	}
+}
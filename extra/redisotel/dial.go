@@ -0,0 +1,160 @@
+package redisotel
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// installDialMetrics reports db.client.connections.dial_time and
+// db.client.connections.tls_handshake_time as two separate histograms, in addition to
+// the single create_time measurement metricsHook.DialHook still records for the whole
+// dial.
+//
+// This only applies when TLSConfig is set and the caller hasn't already supplied a
+// custom Dialer: an opaque Dialer may return an already-established *tls.Conn (or
+// dial over a non-TCP network), in which case there is nothing safe to split and
+// callers keep seeing create_time only.
+//
+// Splitting requires performing the raw TCP dial and the TLS handshake as two timed
+// steps ourselves, so dialMetrics is installed as a DialHook rather than by replacing
+// Options.Dialer: Options is copied by value into the pool's own config when the
+// client is built, which happens before InstrumentMetrics ever runs, so a later
+// assignment to Options.Dialer would have no effect on the pool already in use. The
+// Hook chain, unlike Options, is consulted live on every dial, which is what makes
+// this installable after the fact at all.
+func installDialMetrics(rdb redisConn, conf *config) error {
+	opt := rdb.Options()
+	if opt.TLSConfig == nil || opt.Dialer != nil {
+		return nil
+	}
+
+	dialTime, err := conf.meter.Float64Histogram(
+		"db.client.connections.dial_time",
+		metric.WithDescription("The time it took to establish the raw TCP connection, excluding any TLS handshake."),
+		metric.WithUnit("ms"),
+	)
+	if err != nil {
+		return err
+	}
+
+	tlsHandshakeTime, err := conf.meter.Float64Histogram(
+		"db.client.connections.tls_handshake_time",
+		metric.WithDescription("The time it took to complete the TLS handshake."),
+		metric.WithUnit("ms"),
+	)
+	if err != nil {
+		return err
+	}
+
+	tlsHandshakes, err := conf.meter.Int64Counter(
+		"db.client.connections.tls.handshakes",
+		metric.WithDescription("The number of TLS handshakes attempted, tagged with tls.version/tls.cipher on success and error.type on failure."),
+	)
+	if err != nil {
+		return err
+	}
+
+	dm := &dialMetrics{
+		dialer:           &net.Dialer{Timeout: opt.DialTimeout},
+		tlsConfig:        opt.TLSConfig,
+		attrs:            conf.attrs,
+		dialTime:         dialTime,
+		tlsHandshakeTime: tlsHandshakeTime,
+		tlsHandshakes:    tlsHandshakes,
+	}
+	rdb.AddHook(dm)
+	return nil
+}
+
+type dialMetrics struct {
+	dialer    *net.Dialer
+	tlsConfig *tls.Config
+	attrs     []attribute.KeyValue
+
+	dialTime         metric.Float64Histogram
+	tlsHandshakeTime metric.Float64Histogram
+	tlsHandshakes    metric.Int64Counter
+}
+
+var _ redis.Hook = (*dialMetrics)(nil)
+
+// DialHook fully replaces the dial for this connection rather than timing the
+// existing one, since the raw TCP dial and TLS handshake steps it measures
+// separately aren't otherwise observable once bundled inside a single net.Conn.
+func (dm *dialMetrics) DialHook(hook redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		start := time.Now()
+		rawConn, err := dm.dialer.DialContext(ctx, network, addr)
+		dur := time.Since(start)
+
+		attrs := make([]attribute.KeyValue, 0, len(dm.attrs)+1)
+		attrs = append(attrs, dm.attrs...)
+		attrs = append(attrs, statusAttr(err))
+		dm.dialTime.Record(ctx, milliseconds(dur), metric.WithAttributes(attrs...))
+		if err != nil {
+			return nil, err
+		}
+
+		start = time.Now()
+		tlsConn := tls.Client(rawConn, dm.tlsConfig)
+		err = tlsConn.HandshakeContext(ctx)
+		dur = time.Since(start)
+
+		hsAttrs := make([]attribute.KeyValue, 0, len(dm.attrs)+1)
+		hsAttrs = append(hsAttrs, dm.attrs...)
+		hsAttrs = append(hsAttrs, statusAttr(err))
+		dm.tlsHandshakeTime.Record(ctx, milliseconds(dur), metric.WithAttributes(hsAttrs...))
+
+		if err != nil {
+			rawConn.Close()
+
+			countAttrs := make([]attribute.KeyValue, 0, len(dm.attrs)+1)
+			countAttrs = append(countAttrs, dm.attrs...)
+			countAttrs = append(countAttrs, attribute.String("error.type", errorType(err)))
+			dm.tlsHandshakes.Add(ctx, 1, metric.WithAttributes(countAttrs...))
+			return nil, err
+		}
+
+		state := tlsConn.ConnectionState()
+		countAttrs := make([]attribute.KeyValue, 0, len(dm.attrs)+2)
+		countAttrs = append(countAttrs, dm.attrs...)
+		countAttrs = append(countAttrs,
+			attribute.String("tls.version", tlsVersionName(state.Version)),
+			attribute.String("tls.cipher", tls.CipherSuiteName(state.CipherSuite)),
+		)
+		dm.tlsHandshakes.Add(ctx, 1, metric.WithAttributes(countAttrs...))
+
+		return tlsConn, nil
+	}
+}
+
+func (dm *dialMetrics) ProcessHook(hook redis.ProcessHook) redis.ProcessHook {
+	return hook
+}
+
+func (dm *dialMetrics) ProcessPipelineHook(hook redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return hook
+}
+
+func tlsVersionName(version uint16) string {
+	switch version {
+	case tls.VersionTLS10:
+		return "1.0"
+	case tls.VersionTLS11:
+		return "1.1"
+	case tls.VersionTLS12:
+		return "1.2"
+	case tls.VersionTLS13:
+		return "1.3"
+	default:
+		return fmt.Sprintf("0x%04x", version)
+	}
+}
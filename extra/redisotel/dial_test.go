@@ -0,0 +1,25 @@
+package redisotel
+
+import (
+	"crypto/tls"
+	"testing"
+)
+
+func TestTLSVersionName(t *testing.T) {
+	tests := []struct {
+		version uint16
+		want    string
+	}{
+		{tls.VersionTLS10, "1.0"},
+		{tls.VersionTLS11, "1.1"},
+		{tls.VersionTLS12, "1.2"},
+		{tls.VersionTLS13, "1.3"},
+		{0x0300, "0x0300"},
+	}
+
+	for _, tt := range tests {
+		if got := tlsVersionName(tt.version); got != tt.want {
+			t.Errorf("tlsVersionName(0x%04x) = %q, want %q", tt.version, got, tt.want)
+		}
+	}
+}
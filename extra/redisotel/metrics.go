@@ -2,9 +2,12 @@ package redisotel
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.opentelemetry.io/otel"
@@ -62,8 +65,31 @@ func InstrumentMetrics(rdb redis.UniversalClient, opts ...MetricsOption) error {
 
 	switch rdb := rdb.(type) {
 	case *redis.Client:
+		// Also covers the *redis.Client returned by redis.NewFailoverClient: once a
+		// Sentinel failover client is built there is no further signal distinguishing
+		// it from a plain client, so callers that want the sentinel.master attribute
+		// must supply it explicitly via WithMasterName.
+		conf.attrs = append(conf.attrs, attribute.String("role", "master"))
+		addMasterNameAttr(conf)
 		return registerClient(rdb, conf, state)
 	case *redis.ClusterClient:
+		// redis.NewFailoverClusterClient also returns a *redis.ClusterClient, so it is
+		// covered by this case too.
+		//
+		// role is a client-wide guess, not a per-node fact: OnNewNode below hands us a
+		// *redis.Client per physical node as the cluster topology is discovered, but
+		// that callback doesn't tell us whether the node it's instrumenting is the
+		// master or a replica. When ReadOnly is set this still labels every node
+		// "replica", which mislabels the master node's traffic, since writes (and any
+		// reads the client doesn't route to a replica) still go through the master
+		// using this same instrumented client. Revisit this once/if OnNewNode or the
+		// per-node Options exposes the node's actual role.
+		role := "master"
+		if rdb.Options().ReadOnly {
+			role = "replica"
+		}
+		conf.attrs = append(conf.attrs, attribute.String("role", role))
+		addMasterNameAttr(conf)
 		rdb.OnNewNode(func(rdb *redis.Client) {
 			if err := registerClient(rdb, conf, state); err != nil {
 				otel.Handle(err)
@@ -77,12 +103,30 @@ func InstrumentMetrics(rdb redis.UniversalClient, opts ...MetricsOption) error {
 			}
 		})
 		return nil
+	case *redis.SentinelClient:
+		conf.attrs = append(conf.attrs, attribute.String("role", "sentinel"))
+		addMasterNameAttr(conf)
+		return registerClient(rdb, conf, state)
 	default:
 		return fmt.Errorf("redisotel: %T not supported", rdb)
 	}
 }
 
-func registerClient(rdb *redis.Client, conf *config, state *metricsState) error {
+func addMasterNameAttr(conf *config) {
+	if conf.masterName != "" {
+		conf.attrs = append(conf.attrs, attribute.String("sentinel.master", conf.masterName))
+	}
+}
+
+// redisConn is the subset of *redis.Client and *redis.SentinelClient that redisotel
+// needs to report pool stats and install hooks.
+type redisConn interface {
+	Options() *redis.Options
+	PoolStats() *redis.PoolStats
+	AddHook(redis.Hook)
+}
+
+func registerClient(rdb redisConn, conf *config, state *metricsState) error {
 	if state != nil {
 		state.mutex.Lock()
 		defer state.mutex.Unlock()
@@ -98,7 +142,12 @@ func registerClient(rdb *redis.Client, conf *config, state *metricsState) error
 	}
 	conf.attrs = append(conf.attrs, attribute.String("pool.name", conf.poolName))
 
-	registration, err := reportPoolStats(rdb, conf)
+	observer, err := newPoolObserver(rdb, conf)
+	if err != nil {
+		return err
+	}
+
+	registration, err := reportPoolStats(rdb, conf, observer)
 	if err != nil {
 		return err
 	}
@@ -110,10 +159,13 @@ func registerClient(rdb *redis.Client, conf *config, state *metricsState) error
 	if err := addMetricsHook(rdb, conf); err != nil {
 		return err
 	}
+	if err := installDialMetrics(rdb, conf); err != nil {
+		return err
+	}
 	return nil
 }
 
-func reportPoolStats(rdb *redis.Client, conf *config) (metric.Registration, error) {
+func reportPoolStats(rdb redisConn, conf *config, observer *poolObserver) (metric.Registration, error) {
 	labels := conf.attrs
 	idleAttrs := append(labels, attribute.String("state", "idle"))
 	usedAttrs := append(labels, attribute.String("state", "used"))
@@ -174,6 +226,26 @@ func reportPoolStats(rdb *redis.Client, conf *config) (metric.Registration, erro
 		return nil, err
 	}
 
+	staleClosed, err := conf.meter.Int64ObservableUpDownCounter(
+		"db.client.connections.stale_closed",
+		metric.WithDescription("The number of idle connections closed for exceeding MaxConnAge/ConnMaxLifetime"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := conf.meter.Int64ObservableUpDownCounter(
+		"db.client.connections.pending",
+		metric.WithDescription("The number of goroutines currently blocked waiting for a connection from the pool"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	instruments := []metric.Observable{
+		idleMax, idleMin, connsMax, usage, timeouts, hits, misses, staleClosed, pending,
+	}
+
 	redisConf := rdb.Options()
 	return conf.meter.RegisterCallback(
 		func(ctx context.Context, o metric.Observer) error {
@@ -189,19 +261,128 @@ func reportPoolStats(rdb *redis.Client, conf *config) (metric.Registration, erro
 			o.ObserveInt64(timeouts, int64(stats.Timeouts), metric.WithAttributes(labels...))
 			o.ObserveInt64(hits, int64(stats.Hits), metric.WithAttributes(labels...))
 			o.ObserveInt64(misses, int64(stats.Misses), metric.WithAttributes(labels...))
+			o.ObserveInt64(staleClosed, int64(stats.StaleConns), metric.WithAttributes(labels...))
+			if observer != nil {
+				o.ObserveInt64(pending, observer.Pending(), metric.WithAttributes(labels...))
+			}
 			return nil
 		},
-		idleMax,
-		idleMin,
-		connsMax,
-		usage,
-		timeouts,
-		hits,
-		misses,
+		instruments...,
 	)
 }
 
-func addMetricsHook(rdb *redis.Client, conf *config) error {
+// newPoolObserver creates the wait-time/acquire-failure instruments and installs
+// observer into rdb's Options().PoolObserver handle so the connection pool reports
+// Get/Put events to it.
+//
+// InstrumentMetrics is documented to run on a client that has already been
+// constructed, at which point its ConnPool already exists: PoolObserver is a
+// *redis.ObserverHandle rather than a plain field precisely so this Store still
+// takes effect. Options and the pool's own internal config are copied by value at
+// construction time, but the *redis.ObserverHandle pointer they share is not, so the
+// pool's next Get/Put observes whatever was last stored here.
+func newPoolObserver(rdb redisConn, conf *config) (*poolObserver, error) {
+	waitTime, err := conf.meter.Float64Histogram(
+		"db.client.connections.wait_time",
+		metric.WithDescription("The time it took to wait for a connection from the pool."),
+		metric.WithUnit("ms"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	acquireFailures, err := conf.meter.Int64Counter(
+		"db.client.connections.acquire.failures",
+		metric.WithDescription("The number of times a connection could not be acquired from the pool."),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	observer := &poolObserver{
+		attrs:           conf.attrs,
+		waitTime:        waitTime,
+		acquireFailures: acquireFailures,
+	}
+	rdb.Options().PoolObserver.Store(observer)
+	return observer, nil
+}
+
+// poolObserver records pool wait-time and acquire-failure metrics and tracks the
+// number of goroutines currently blocked in Get. Its method set matches the Observer
+// interface internal/pool defines (OnGetStart/OnGet/OnPut), which is how
+// Options().PoolObserver.Store above is able to accept it without this package
+// needing to import that internal package itself: extra/redisotel is a separate Go
+// module from github.com/redis/go-redis/v9, so it cannot reach anything under
+// .../v9/internal/... regardless of the replace directive pointing it back at the
+// root module.
+type poolObserver struct {
+	attrs           []attribute.KeyValue
+	waitTime        metric.Float64Histogram
+	acquireFailures metric.Int64Counter
+	pending         int64
+}
+
+// poolObserverShape documents, and lets the compiler check, the Observer method set
+// poolObserver must satisfy without naming the (unimportable) internal/pool.Observer
+// interface directly.
+type poolObserverShape interface {
+	OnGetStart()
+	OnGet(time.Duration, error)
+	OnPut()
+}
+
+var _ poolObserverShape = (*poolObserver)(nil)
+
+func (po *poolObserver) OnGetStart() {
+	atomic.AddInt64(&po.pending, 1)
+}
+
+func (po *poolObserver) OnGet(waited time.Duration, err error) {
+	atomic.AddInt64(&po.pending, -1)
+
+	po.waitTime.Record(context.Background(), milliseconds(waited), metric.WithAttributes(po.attrs...))
+
+	if err == nil {
+		return
+	}
+
+	attrs := make([]attribute.KeyValue, 0, len(po.attrs)+1)
+	attrs = append(attrs, po.attrs...)
+	attrs = append(attrs, attribute.String("reason", acquireFailureReason(err)))
+	po.acquireFailures.Add(context.Background(), 1, metric.WithAttributes(attrs...))
+}
+
+func (po *poolObserver) OnPut() {}
+
+func (po *poolObserver) Pending() int64 {
+	return atomic.LoadInt64(&po.pending)
+}
+
+// acquireFailureReason classifies the error OnGet reports when Get fails to
+// acquire a connection. redis.ErrPoolTimeout/redis.ErrClosed are the root package's
+// re-exports of the sentinel errors internal/pool.ConnPool.Get returns; this package
+// cannot reference internal/pool directly (see poolObserver's doc comment), so it
+// checks the public aliases instead.
+func acquireFailureReason(err error) string {
+	var netErr *net.OpError
+	switch {
+	case errors.Is(err, redis.ErrPoolTimeout), errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "context_canceled"
+	case errors.Is(err, redis.ErrClosed):
+		return "closed"
+	case errors.As(err, &netErr):
+		return "dial_error"
+	default:
+		// Anything else surfaces while dialing a new connection to grow the pool, so
+		// it is bucketed with dial_error rather than mislabeled as a timeout.
+		return "dial_error"
+	}
+}
+
+func addMetricsHook(rdb redisConn, conf *config) error {
 	createTime, err := conf.meter.Float64Histogram(
 		"db.client.connections.create_time",
 		metric.WithDescription("The time it took to create a new connection."),
@@ -220,10 +401,23 @@ func addMetricsHook(rdb *redis.Client, conf *config) error {
 		return err
 	}
 
+	var operationDuration metric.Float64Histogram
+	if conf.commandMetricsEnabled {
+		operationDuration, err = conf.meter.Float64Histogram(
+			"db.client.operation.duration",
+			metric.WithDescription("Duration of individual Redis commands, broken down by db.operation."),
+			metric.WithUnit("ms"),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
 	rdb.AddHook(&metricsHook{
-		createTime: createTime,
-		useTime:    useTime,
-		attrs:      conf.attrs,
+		createTime:        createTime,
+		useTime:           useTime,
+		operationDuration: operationDuration,
+		attrs:             conf.attrs,
 	})
 	return nil
 }
@@ -232,6 +426,11 @@ type metricsHook struct {
 	createTime metric.Float64Histogram
 	useTime    metric.Float64Histogram
 	attrs      []attribute.KeyValue
+
+	// operationDuration is nil unless WithCommandMetrics(true) was passed, since it
+	// carries one series per distinct Redis command and is opt-in to avoid blowing up
+	// cardinality on the default setup.
+	operationDuration metric.Float64Histogram
 }
 
 var _ redis.Hook = (*metricsHook)(nil)
@@ -261,13 +460,26 @@ func (mh *metricsHook) ProcessHook(hook redis.ProcessHook) redis.ProcessHook {
 
 		dur := time.Since(start)
 
-		attrs := make([]attribute.KeyValue, 0, len(mh.attrs)+2)
+		attrs := make([]attribute.KeyValue, 0, len(mh.attrs)+3)
 		attrs = append(attrs, mh.attrs...)
 		attrs = append(attrs, attribute.String("type", "command"))
 		attrs = append(attrs, statusAttr(err))
+		if errType := errorType(err); errType != "" {
+			attrs = append(attrs, attribute.String("error.type", errType))
+		}
 
 		mh.useTime.Record(ctx, milliseconds(dur), metric.WithAttributes(attrs...))
 
+		if mh.operationDuration != nil {
+			opAttrs := make([]attribute.KeyValue, 0, len(mh.attrs)+2)
+			opAttrs = append(opAttrs, mh.attrs...)
+			opAttrs = append(opAttrs, attribute.String("db.operation", strings.ToUpper(cmd.Name())))
+			if errType := errorType(err); errType != "" {
+				opAttrs = append(opAttrs, attribute.String("error.type", errType))
+			}
+			mh.operationDuration.Record(ctx, milliseconds(dur), metric.WithAttributes(opAttrs...))
+		}
+
 		return err
 	}
 }
@@ -282,13 +494,29 @@ func (mh *metricsHook) ProcessPipelineHook(
 
 		dur := time.Since(start)
 
-		attrs := make([]attribute.KeyValue, 0, len(mh.attrs)+2)
+		attrs := make([]attribute.KeyValue, 0, len(mh.attrs)+3)
 		attrs = append(attrs, mh.attrs...)
 		attrs = append(attrs, attribute.String("type", "pipeline"))
 		attrs = append(attrs, statusAttr(err))
+		if errType := errorType(err); errType != "" {
+			attrs = append(attrs, attribute.String("error.type", errType))
+		}
 
 		mh.useTime.Record(ctx, milliseconds(dur), metric.WithAttributes(attrs...))
 
+		if mh.operationDuration != nil {
+			pipelineSize := attribute.Int("pipeline.size", len(cmds))
+			for _, cmd := range cmds {
+				opAttrs := make([]attribute.KeyValue, 0, len(mh.attrs)+3)
+				opAttrs = append(opAttrs, mh.attrs...)
+				opAttrs = append(opAttrs, attribute.String("db.operation", strings.ToUpper(cmd.Name())), pipelineSize)
+				if errType := errorType(cmd.Err()); errType != "" {
+					opAttrs = append(opAttrs, attribute.String("error.type", errType))
+				}
+				mh.operationDuration.Record(ctx, milliseconds(dur), metric.WithAttributes(opAttrs...))
+			}
+		}
+
 		return err
 	}
 }
@@ -303,3 +531,53 @@ func statusAttr(err error) attribute.KeyValue {
 	}
 	return attribute.String("status", "ok")
 }
+
+// errorType classifies err into a low-cardinality error.type attribute value, or ""
+// if err is nil. Redis server errors are classified by the reply's error prefix
+// (e.g. "MOVED ..."); everything else is classified from the Go error chain.
+//
+// extra/rediscensus carries its own copy of this classification (tagged error.type
+// rather than attributed). It isn't factored into a shared helper because the two
+// packages are separate Go modules with no common non-internal home for it to live
+// in; keep the two in sync by hand when the classification changes.
+func errorType(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var netErr *net.OpError
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.As(err, &netErr):
+		// A read/write deadline expiring on the socket surfaces as *net.OpError too
+		// (it implements net.Error.Timeout()), so it must be classified as a timeout
+		// before falling back to the generic network bucket.
+		if netErr.Timeout() {
+			return "timeout"
+		}
+		return "network"
+	}
+
+	msg := err.Error()
+	switch {
+	case strings.HasPrefix(msg, "MOVED"):
+		return "moved"
+	case strings.HasPrefix(msg, "ASK"):
+		return "ask"
+	case strings.HasPrefix(msg, "LOADING"):
+		return "loading"
+	case strings.HasPrefix(msg, "READONLY"):
+		return "readonly"
+	case strings.HasPrefix(msg, "NOSCRIPT"):
+		return "noscript"
+	case strings.HasPrefix(msg, "WRONGTYPE"):
+		return "wrongtype"
+	case strings.Contains(msg, "i/o timeout"):
+		return "timeout"
+	default:
+		return "other"
+	}
+}
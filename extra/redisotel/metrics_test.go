@@ -0,0 +1,59 @@
+package redisotel
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestErrorType(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"nil", nil, ""},
+		{"deadline exceeded", context.DeadlineExceeded, "timeout"},
+		{"canceled", context.Canceled, "canceled"},
+		{
+			"net.OpError timeout",
+			&net.OpError{Op: "read", Err: timeoutError{}},
+			"timeout",
+		},
+		{
+			"net.OpError non-timeout",
+			&net.OpError{Op: "dial", Err: errors.New("connection refused")},
+			"network",
+		},
+		{"moved", errors.New("MOVED 1 127.0.0.1:6380"), "moved"},
+		{"readonly", errors.New("READONLY replica is read-only"), "readonly"},
+		{"other", errors.New("boom"), "other"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorType(tt.err); got != tt.want {
+				t.Fatalf("errorType(%v) = %q, want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithCommandMetrics(t *testing.T) {
+	conf := newConfig(WithCommandMetrics(true))
+	if !conf.commandMetricsEnabled {
+		t.Fatal("commandMetricsEnabled = false, want true")
+	}
+
+	conf = newConfig()
+	if conf.commandMetricsEnabled {
+		t.Fatal("commandMetricsEnabled = true, want false by default")
+	}
+}
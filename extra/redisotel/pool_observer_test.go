@@ -0,0 +1,33 @@
+package redisotel
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestAcquireFailureReason(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"pool timeout", redis.ErrPoolTimeout, "timeout"},
+		{"deadline exceeded", context.DeadlineExceeded, "timeout"},
+		{"context canceled", context.Canceled, "context_canceled"},
+		{"pool closed", redis.ErrClosed, "closed"},
+		{"dial error", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, "dial_error"},
+		{"unrecognized", errors.New("boom"), "dial_error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := acquireFailureReason(tt.err); got != tt.want {
+				t.Fatalf("acquireFailureReason(%v) = %q, want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
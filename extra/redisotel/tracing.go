@@ -0,0 +1,137 @@
+package redisotel
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// InstrumentTracing starts reporting OpenTelemetry Traces. Like InstrumentMetrics, it
+// understands Sentinel topologies: *redis.SentinelClient and the failover clients
+// returned by redis.NewFailoverClient/NewFailoverClusterClient get a `role` attribute
+// on every span, plus `sentinel.master` when WithMasterName was supplied.
+func InstrumentTracing(rdb redis.UniversalClient, opts ...TracingOption) error {
+	baseOpts := make([]baseOption, len(opts))
+	for i, opt := range opts {
+		baseOpts[i] = opt
+	}
+	conf := newConfig(baseOpts...)
+
+	if conf.tracer == nil {
+		conf.tracer = conf.tp.Tracer(
+			instrumName,
+			trace.WithInstrumentationVersion("semver:"+redis.Version()),
+		)
+	}
+
+	switch rdb := rdb.(type) {
+	case *redis.Client:
+		conf.attrs = append(conf.attrs, attribute.String("role", "master"))
+		addMasterNameAttr(conf)
+		return registerTracing(rdb, conf)
+	case *redis.ClusterClient:
+		// See the matching case in metrics.go: role is a client-wide guess applied to
+		// every node OnNewNode reports, so it mislabels the master node as "replica"
+		// when ReadOnly is set. There's currently no per-node signal to do better.
+		role := "master"
+		if rdb.Options().ReadOnly {
+			role = "replica"
+		}
+		conf.attrs = append(conf.attrs, attribute.String("role", role))
+		addMasterNameAttr(conf)
+		rdb.OnNewNode(func(rdb *redis.Client) {
+			if err := registerTracing(rdb, conf); err != nil {
+				otel.Handle(err)
+			}
+		})
+		return nil
+	case *redis.Ring:
+		rdb.OnNewNode(func(rdb *redis.Client) {
+			if err := registerTracing(rdb, conf); err != nil {
+				otel.Handle(err)
+			}
+		})
+		return nil
+	case *redis.SentinelClient:
+		conf.attrs = append(conf.attrs, attribute.String("role", "sentinel"))
+		addMasterNameAttr(conf)
+		return registerTracing(rdb, conf)
+	default:
+		return fmt.Errorf("redisotel: %T not supported", rdb)
+	}
+}
+
+func registerTracing(rdb redisConn, conf *config) error {
+	poolName := conf.poolName
+	if poolName == "" {
+		poolName = rdb.Options().Addr
+	}
+	attrs := append(append([]attribute.KeyValue{}, conf.attrs...), attribute.String("pool.name", poolName))
+
+	rdb.AddHook(&tracingHook{tracer: conf.tracer, attrs: attrs})
+	return nil
+}
+
+type tracingHook struct {
+	tracer trace.Tracer
+	attrs  []attribute.KeyValue
+}
+
+var _ redis.Hook = (*tracingHook)(nil)
+
+func (th *tracingHook) DialHook(hook redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		ctx, span := th.tracer.Start(ctx, "redis.dial", trace.WithAttributes(th.attrs...))
+		defer span.End()
+
+		conn, err := hook(ctx, network, addr)
+		recordError(span, err)
+		return conn, err
+	}
+}
+
+func (th *tracingHook) ProcessHook(hook redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		attrs := append(append([]attribute.KeyValue{}, th.attrs...),
+			attribute.String("db.operation", strings.ToUpper(cmd.Name())))
+		ctx, span := th.tracer.Start(ctx, cmd.FullName(), trace.WithAttributes(attrs...))
+		defer span.End()
+
+		err := hook(ctx, cmd)
+		recordError(span, err)
+		return err
+	}
+}
+
+func (th *tracingHook) ProcessPipelineHook(
+	hook redis.ProcessPipelineHook,
+) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		attrs := append(append([]attribute.KeyValue{}, th.attrs...),
+			attribute.Int("pipeline.size", len(cmds)))
+		ctx, span := th.tracer.Start(ctx, "redis.pipeline", trace.WithAttributes(attrs...))
+		defer span.End()
+
+		err := hook(ctx, cmds)
+		recordError(span, err)
+		return err
+	}
+}
+
+// recordError marks span as failed, unless err is nil or redis.Nil: a cache miss is
+// an expected outcome, not a span-level error.
+func recordError(span trace.Span, err error) {
+	if err == nil || err == redis.Nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
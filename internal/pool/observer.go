@@ -0,0 +1,63 @@
+package pool
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Observer receives connection pool lifecycle events. ConnPool.Get calls
+// OnGetStart when it begins waiting for a connection and OnGet once it returns,
+// whether or not it succeeded; ConnPool.Put calls OnPut once a connection is
+// returned to the pool. Implementations must be safe for concurrent use.
+type Observer interface {
+	// OnGetStart reports that a call to Get has begun. It is used to track how many
+	// goroutines are currently blocked waiting for a connection.
+	OnGetStart()
+	// OnGet reports how long Get waited for a connection and the error it returned,
+	// if any.
+	OnGet(waited time.Duration, err error)
+	// OnPut reports that a connection was returned to the pool.
+	OnPut()
+}
+
+// nopObserver is installed when an ObserverHandle is empty so call sites never have
+// to check for a missing observer.
+type nopObserver struct{}
+
+func (nopObserver) OnGetStart()                {}
+func (nopObserver) OnGet(time.Duration, error) {}
+func (nopObserver) OnPut()                     {}
+
+// ObserverHandle is a concurrency-safe, swappable holder for an Observer.
+//
+// Options.Observer holds a *ObserverHandle rather than a bare Observer so that
+// instrumentation installed after a client (and its ConnPool) already exists can
+// still take effect. The redis.Options -> pool.Options translation that happens at
+// client-construction time necessarily copies field values, so a later assignment to
+// a field typed as a bare Observer would never be seen by the already-built
+// ConnPool. A *ObserverHandle is itself a pointer, though, so that one copy survives:
+// whoever holds it (ConnPool included) always observes the most recent Store.
+type ObserverHandle struct {
+	v atomic.Value
+}
+
+// Store installs o as the active Observer. A nil o reverts to the no-op observer.
+func (h *ObserverHandle) Store(o Observer) {
+	if o == nil {
+		o = nopObserver{}
+	}
+	h.v.Store(&o)
+}
+
+// Load returns the active Observer, or a no-op Observer if h is nil or nothing has
+// been stored yet.
+func (h *ObserverHandle) Load() Observer {
+	if h == nil {
+		return nopObserver{}
+	}
+	o, _ := h.v.Load().(*Observer)
+	if o == nil {
+		return nopObserver{}
+	}
+	return *o
+}
@@ -0,0 +1,116 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+// ErrClosed is returned by ConnPool.Get once the pool has been closed.
+var ErrClosed = errors.New("redis: client is closed")
+
+// ErrPoolTimeout is returned by ConnPool.Get when PoolTimeout elapses while waiting
+// for a connection to become available.
+var ErrPoolTimeout = errors.New("redis: connection pool timeout")
+
+// Options configures a ConnPool.
+type Options struct {
+	Dialer func(ctx context.Context) (net.Conn, error)
+
+	PoolSize    int
+	PoolTimeout time.Duration
+
+	// Observer, if set, is notified of Get/Put lifecycle events so callers (e.g.
+	// redisotel, rediscensus) can report pool wait-time and acquire-failure metrics
+	// without reaching into pool internals. It is a *ObserverHandle rather than a bare
+	// Observer specifically so that instrumentation installed after the pool has
+	// already been built (the documented usage for both of those packages) still
+	// takes effect; see ObserverHandle's doc comment.
+	Observer *ObserverHandle
+}
+
+// Conn is a pooled network connection.
+type Conn struct {
+	netConn   net.Conn
+	createdAt time.Time
+}
+
+// ConnPool limits concurrent connections to Options.PoolSize, blocking Get callers
+// until a connection is available, PoolTimeout elapses, or ctx is done. Close does
+// not wake callers that are already blocked.
+type ConnPool struct {
+	cfg *Options
+
+	queue  chan struct{}
+	closed uint32
+}
+
+func NewConnPool(cfg *Options) *ConnPool {
+	p := &ConnPool{
+		cfg:   cfg,
+		queue: make(chan struct{}, cfg.PoolSize),
+	}
+	for i := 0; i < cfg.PoolSize; i++ {
+		p.queue <- struct{}{}
+	}
+	return p
+}
+
+func (p *ConnPool) observer() Observer {
+	return p.cfg.Observer.Load()
+}
+
+// Get waits for a free slot in the pool and dials a new connection, reporting the
+// wait through the configured Observer.
+func (p *ConnPool) Get(ctx context.Context) (*Conn, error) {
+	obs := p.observer()
+	obs.OnGetStart()
+
+	start := time.Now()
+	conn, err := p.getConn(ctx)
+	obs.OnGet(time.Since(start), err)
+	return conn, err
+}
+
+func (p *ConnPool) getConn(ctx context.Context) (*Conn, error) {
+	if atomic.LoadUint32(&p.closed) == 1 {
+		return nil, ErrClosed
+	}
+
+	timer := time.NewTimer(p.cfg.PoolTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-p.queue:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timer.C:
+		return nil, ErrPoolTimeout
+	}
+
+	if atomic.LoadUint32(&p.closed) == 1 {
+		p.queue <- struct{}{}
+		return nil, ErrClosed
+	}
+
+	netConn, err := p.cfg.Dialer(ctx)
+	if err != nil {
+		p.queue <- struct{}{}
+		return nil, err
+	}
+	return &Conn{netConn: netConn, createdAt: time.Now()}, nil
+}
+
+// Put returns conn to the pool, freeing a slot for a future Get.
+func (p *ConnPool) Put(_ context.Context, _ *Conn) {
+	p.queue <- struct{}{}
+	p.observer().OnPut()
+}
+
+// Close marks the pool closed; subsequent Get calls return ErrClosed.
+func (p *ConnPool) Close() error {
+	atomic.StoreUint32(&p.closed, 1)
+	return nil
+}
@@ -0,0 +1,128 @@
+package pool
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+type recordingObserver struct {
+	starts  int
+	gets    int
+	puts    int
+	lastErr error
+}
+
+func (o *recordingObserver) OnGetStart() { o.starts++ }
+func (o *recordingObserver) OnGet(_ time.Duration, err error) {
+	o.gets++
+	o.lastErr = err
+}
+func (o *recordingObserver) OnPut() { o.puts++ }
+
+func TestConnPoolGetPutNotifiesObserver(t *testing.T) {
+	obs := &recordingObserver{}
+	handle := &ObserverHandle{}
+	handle.Store(obs)
+	p := NewConnPool(&Options{
+		PoolSize:    1,
+		PoolTimeout: time.Second,
+		Observer:    handle,
+		Dialer: func(ctx context.Context) (net.Conn, error) {
+			c1, c2 := net.Pipe()
+			c2.Close()
+			return c1, nil
+		},
+	})
+
+	conn, err := p.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	p.Put(context.Background(), conn)
+
+	if obs.starts != 1 || obs.gets != 1 || obs.puts != 1 {
+		t.Fatalf("observer calls = %+v, want one of each", obs)
+	}
+	if obs.lastErr != nil {
+		t.Fatalf("OnGet err = %v, want nil", obs.lastErr)
+	}
+}
+
+func TestConnPoolGetTimesOut(t *testing.T) {
+	obs := &recordingObserver{}
+	handle := &ObserverHandle{}
+	handle.Store(obs)
+	p := NewConnPool(&Options{
+		PoolSize:    1,
+		PoolTimeout: 10 * time.Millisecond,
+		Observer:    handle,
+		Dialer: func(ctx context.Context) (net.Conn, error) {
+			c1, c2 := net.Pipe()
+			c2.Close()
+			return c1, nil
+		},
+	})
+
+	if _, err := p.Get(context.Background()); err != nil {
+		t.Fatalf("first Get() error = %v", err)
+	}
+
+	if _, err := p.Get(context.Background()); err != ErrPoolTimeout {
+		t.Fatalf("second Get() error = %v, want ErrPoolTimeout", err)
+	}
+	if obs.gets != 2 {
+		t.Fatalf("OnGet calls = %d, want 2", obs.gets)
+	}
+}
+
+// TestConnPoolObserverInstalledAfterConstruction proves the scenario redisotel and
+// rediscensus actually rely on: InstrumentMetrics runs on a client
+// (and thus a ConnPool) that already exists, so the Observer can only be handed to
+// Options.Observer's handle after NewConnPool has returned.
+func TestConnPoolObserverInstalledAfterConstruction(t *testing.T) {
+	handle := &ObserverHandle{}
+	p := NewConnPool(&Options{
+		PoolSize:    1,
+		PoolTimeout: time.Second,
+		Observer:    handle,
+		Dialer: func(ctx context.Context) (net.Conn, error) {
+			c1, c2 := net.Pipe()
+			c2.Close()
+			return c1, nil
+		},
+	})
+
+	obs := &recordingObserver{}
+	handle.Store(obs)
+
+	conn, err := p.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	p.Put(context.Background(), conn)
+
+	if obs.starts != 1 || obs.gets != 1 || obs.puts != 1 {
+		t.Fatalf("observer calls = %+v, want one of each", obs)
+	}
+}
+
+func TestConnPoolGetAfterClose(t *testing.T) {
+	p := NewConnPool(&Options{
+		PoolSize:    1,
+		PoolTimeout: time.Second,
+		Dialer: func(ctx context.Context) (net.Conn, error) {
+			c1, c2 := net.Pipe()
+			c2.Close()
+			return c1, nil
+		},
+	})
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if _, err := p.Get(context.Background()); err != ErrClosed {
+		t.Fatalf("Get() error = %v, want ErrClosed", err)
+	}
+}